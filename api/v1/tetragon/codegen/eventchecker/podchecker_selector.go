@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+package eventchecker
+
+import (
+	"fmt"
+
+	"github.com/cilium/tetragon/api/v1/tetragon"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podSelectors holds the selector-based pod checks added on top of the
+// generated PodChecker in podchecker.go. It's embedded into PodChecker so that this
+// hand-written file, not the generated one, owns both the field and the
+// matching logic for every selector-based check we add.
+type podSelectors struct {
+	// PodLabelSelector is populated via WithPodLabelSelector.
+	PodLabelSelector labels.Selector `json:"-"`
+}
+
+func (s *podSelectors) check(event *tetragon.Pod) error {
+	if s.PodLabelSelector != nil {
+		if !s.PodLabelSelector.Matches(labels.Set(event.PodLabels)) {
+			return fmt.Errorf("PodChecker: PodLabelSelector check failed: selector %q does not match pod labels %v", s.PodLabelSelector, event.PodLabels)
+		}
+	}
+	return nil
+}
+
+// WithPodLabelSelector adds a check that the pod's labels satisfy the given
+// Kubernetes label selector expression, e.g. "app=frontend,tier!=backend"
+// or "environment in (prod, staging)". See the Kubernetes documentation on
+// labels and selectors for the full grammar:
+// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#label-selectors
+//
+// It panics if selector fails to parse, since checkers are built once at
+// test-definition time from statically known strings.
+func (checker *PodChecker) WithPodLabelSelector(selector string) *PodChecker {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		panic(fmt.Sprintf("eventchecker: invalid pod label selector %q: %v", selector, err))
+	}
+	checker.PodLabelSelector = parsed
+	return checker
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+package eventchecker
+
+import (
+	"fmt"
+
+	"github.com/cilium/tetragon/pkg/matchers/stringmatcher"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PodLogChecker asserts that a pod selected by LabelSelector produced a log
+// line matching Match. Unlike the other checkers in this package it does
+// not check a Tetragon gRPC event: it's driven directly by a LogScraper
+// (see tests/e2e/checker) that tails application pod logs and feeds
+// matching lines back into the same RPCChecker pipeline.
+type PodLogChecker struct {
+	LabelSelector labels.Selector
+	Match         stringmatcher.StringMatcher
+}
+
+// NewPodLogChecker creates a PodLogChecker that matches log lines from pods
+// selected by labelSelector (standard Kubernetes label selector syntax)
+// against match. It panics if labelSelector fails to parse, since checkers
+// are built once at test-definition time from statically known strings.
+func NewPodLogChecker(labelSelector string, match stringmatcher.StringMatcher) *PodLogChecker {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		panic(fmt.Sprintf("eventchecker: invalid pod label selector %q: %v", labelSelector, err))
+	}
+	return &PodLogChecker{LabelSelector: selector, Match: match}
+}
+
+// Check reports whether line, produced by a pod with the given labels,
+// satisfies this checker. It returns an error both when the pod is not
+// selected by LabelSelector and when the line doesn't match, so callers
+// that only care about "did anything match" can treat a nil error as a hit.
+func (checker *PodLogChecker) Check(podLabels map[string]string, line string) error {
+	if !checker.LabelSelector.Matches(labels.Set(podLabels)) {
+		return fmt.Errorf("PodLogChecker: pod labels %v do not match selector %q", podLabels, checker.LabelSelector)
+	}
+	if err := checker.Match.Match(line); err != nil {
+		return fmt.Errorf("PodLogChecker: line check failed: %w", err)
+	}
+	return nil
+}
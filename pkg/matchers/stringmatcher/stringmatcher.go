@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+// Package stringmatcher provides a StringMatcher type used by event
+// checkers to assert on string fields using different match strategies
+// (exact, prefix, suffix, regex).
+package stringmatcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Operator identifies the matching strategy used by a StringMatcher.
+type Operator string
+
+const (
+	OpFull   Operator = "Full"
+	OpPrefix Operator = "Prefix"
+	OpSuffix Operator = "Suffix"
+	OpRegex  Operator = "Regex"
+)
+
+// StringMatcher matches a string using one of a handful of strategies. It
+// is typically constructed via the Full, Prefix, Suffix or Regex helpers
+// and embedded into generated event checkers.
+type StringMatcher struct {
+	Operator Operator `json:"operator"`
+	Value    string   `json:"value"`
+
+	regex *regexp.Regexp
+}
+
+// Full returns a StringMatcher that requires an exact match.
+func Full(value string) *StringMatcher {
+	return &StringMatcher{Operator: OpFull, Value: value}
+}
+
+// Prefix returns a StringMatcher that requires the candidate to start with value.
+func Prefix(value string) *StringMatcher {
+	return &StringMatcher{Operator: OpPrefix, Value: value}
+}
+
+// Suffix returns a StringMatcher that requires the candidate to end with value.
+func Suffix(value string) *StringMatcher {
+	return &StringMatcher{Operator: OpSuffix, Value: value}
+}
+
+// Regex returns a StringMatcher that requires the candidate to match the
+// given regular expression. It panics if the expression does not compile,
+// matching the behavior of other checker constructors that are only ever
+// called with statically known patterns.
+func Regex(pattern string) *StringMatcher {
+	return &StringMatcher{Operator: OpRegex, Value: pattern, regex: regexp.MustCompile(pattern)}
+}
+
+// Match reports whether value satisfies the matcher.
+func (m *StringMatcher) Match(value string) error {
+	switch m.Operator {
+	case OpFull:
+		if value != m.Value {
+			return fmt.Errorf("string %q does not equal expected value %q", value, m.Value)
+		}
+	case OpPrefix:
+		if !strings.HasPrefix(value, m.Value) {
+			return fmt.Errorf("string %q does not have expected prefix %q", value, m.Value)
+		}
+	case OpSuffix:
+		if !strings.HasSuffix(value, m.Value) {
+			return fmt.Errorf("string %q does not have expected suffix %q", value, m.Value)
+		}
+	case OpRegex:
+		re := m.regex
+		if re == nil {
+			re = regexp.MustCompile(m.Value)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("string %q does not match expected pattern %q", value, m.Value)
+		}
+	default:
+		return fmt.Errorf("unknown string matcher operator %q", m.Operator)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+package checker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+
+	"github.com/cilium/tetragon/tests/e2e/helpers"
+)
+
+// tetragonConn is a gRPC connection to a Tetragon pod's event server,
+// tunneled through a local port-forward. Close tears down both.
+type tetragonConn struct {
+	*grpc.ClientConn
+	stopForward chan struct{}
+}
+
+func (c *tetragonConn) Close() error {
+	close(c.stopForward)
+	return c.ClientConn.Close()
+}
+
+// dialTetragon finds a Tetragon pod in the cluster, port-forwards to its
+// gRPC event server, and dials it.
+func dialTetragon(ctx context.Context, c *envconf.Config) (*tetragonConn, error) {
+	clientset, err := helpers.Clientset(c)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := helpers.ListPodsByLabelSelector(ctx, clientset, helpers.TetragonNamespace, helpers.TetragonAppSelector)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no tetragon pods found in namespace %q matching %q", helpers.TetragonNamespace, helpers.TetragonAppSelector)
+	}
+	pod := pods[0]
+
+	localPort, stopForward, err := portForward(c.Client().RESTConfig(), pod.Namespace, pod.Name, helpers.TetragonGRPCPort)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", localPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		close(stopForward)
+		return nil, fmt.Errorf("failed to dial tetragon pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+
+	return &tetragonConn{ClientConn: conn, stopForward: stopForward}, nil
+}
+
+// portForward opens a port-forward from an ephemeral local port to
+// remotePort on the named pod, and returns the local port that was chosen.
+// The returned channel stops the forward when closed.
+func portForward(restConfig *rest.Config, namespace, podName string, remotePort int) (int, chan struct{}, error) {
+	roundTripper, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to build spdy round tripper: %w", err)
+	}
+
+	serverURL := url.URL{
+		Scheme: "https",
+		Path:   fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/portforward", namespace, podName),
+		Host:   strings.TrimPrefix(restConfig.Host, "https://"),
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, http.MethodPost, &serverURL)
+
+	stopChan := make(chan struct{})
+	readyChan := make(chan struct{})
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("0:%d", remotePort)}, stopChan, readyChan, out, errOut)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to set up port-forward to %s/%s: %w", namespace, podName, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- forwarder.ForwardPorts() }()
+
+	select {
+	case <-readyChan:
+	case err := <-errCh:
+		return 0, nil, fmt.Errorf("port-forward to %s/%s failed: %w (%s)", namespace, podName, err, errOut.String())
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		close(stopChan)
+		return 0, nil, fmt.Errorf("failed to get forwarded port: %w", err)
+	}
+
+	return int(ports[0].Local), stopChan, nil
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+package checker
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/cilium/tetragon/pkg/logger"
+
+	ec "github.com/cilium/tetragon/api/v1/tetragon/codegen/eventchecker"
+)
+
+// watchRetryBackoff is how long watchLoop waits before re-establishing the
+// pod watch after the API server closes it (e.g. on resourceVersion
+// expiry), and how long tailPod waits before re-attaching to a pod's logs
+// after its stream ends.
+const watchRetryBackoff = 2 * time.Second
+
+// LogScraper tails the logs of every pod matching a label selector in a
+// namespace and feeds matching lines into an RPCChecker's pipeline as a
+// secondary assertion source, alongside the Tetragon gRPC event stream.
+type LogScraper struct {
+	clientset     kubernetes.Interface
+	namespace     string
+	labelSelector string
+	checks        []*ec.PodLogChecker
+	rpcChecker    *RPCChecker
+
+	wg sync.WaitGroup
+}
+
+// NewLogScraper creates a LogScraper that tails pods in namespace matching
+// labelSelector and matches their log lines against checks. Every check is
+// registered with rpcChecker as a condition of the checker's overall
+// success: CheckInNamespace fails the test if a check never matches, and
+// each match also resets rpcChecker's idle timeout, keeping the checker
+// alive as long as the workload is still producing expected log output.
+func NewLogScraper(clientset kubernetes.Interface, rpcChecker *RPCChecker, namespace, labelSelector string, checks ...*ec.PodLogChecker) *LogScraper {
+	rpcChecker.withExpectedLogChecks(checks...)
+	return &LogScraper{
+		clientset:     clientset,
+		namespace:     namespace,
+		labelSelector: labelSelector,
+		checks:        checks,
+		rpcChecker:    rpcChecker,
+	}
+}
+
+// Start begins watching namespace for pods matching the scraper's label
+// selector, tailing the logs of each one until ctx is canceled. It returns
+// once the initial watch is established; pod tailing happens in background
+// goroutines tracked by s.wg.
+func (s *LogScraper) Start(ctx context.Context) error {
+	watcher, err := s.clientset.CoreV1().Pods(s.namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector: s.labelSelector,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go s.watchLoop(ctx, watcher)
+	return nil
+}
+
+// Wait blocks until every pod-tailing goroutine spawned by this scraper has
+// returned, which happens when ctx is canceled.
+func (s *LogScraper) Wait() {
+	s.wg.Wait()
+}
+
+// watchLoop consumes pod add/modify events from watcher and spawns a
+// tailPod goroutine for each pod it hasn't seen yet. If the watch itself
+// closes (e.g. the API server drops it on resourceVersion expiry), it
+// re-establishes a fresh watch rather than giving up on discovering new
+// pods for the rest of the test.
+func (s *LogScraper) watchLoop(ctx context.Context, watcher watch.Interface) {
+	defer s.wg.Done()
+
+	seen := make(map[string]bool)
+	for {
+		closed := s.consumeWatch(ctx, watcher, seen)
+		watcher.Stop()
+		if !closed {
+			// ctx was canceled, not the watch itself.
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryBackoff):
+		}
+
+		next, err := s.clientset.CoreV1().Pods(s.namespace).Watch(ctx, metav1.ListOptions{
+			LabelSelector: s.labelSelector,
+		})
+		if err != nil {
+			logger.GetLogger().WithError(err).Debug("LogScraper: failed to re-establish pod watch, will retry")
+			continue
+		}
+		watcher = next
+	}
+}
+
+// consumeWatch reads pod add/modify events from watcher, spawning a
+// tailPod goroutine for each pod not already in seen, until either the
+// watch's result channel closes (returns true, so the caller re-watches)
+// or ctx is canceled (returns false).
+func (s *LogScraper) consumeWatch(ctx context.Context, watcher watch.Interface, seen map[string]bool) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return true
+			}
+			pod, ok := event.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			if seen[pod.Name] {
+				continue
+			}
+			seen[pod.Name] = true
+
+			s.wg.Add(1)
+			go s.tailPod(ctx, pod.Name, pod.Labels)
+		}
+	}
+}
+
+// tailPod streams logs for pod and checks every line against s.checks,
+// re-attaching after every stream end (e.g. a container restart) until ctx
+// is canceled, so a pod being recycled mid-test doesn't silently stop
+// coverage of it.
+func (s *LogScraper) tailPod(ctx context.Context, podName string, podLabels map[string]string) {
+	defer s.wg.Done()
+
+	for {
+		if err := s.streamOnce(ctx, podName, podLabels); err != nil {
+			logger.GetLogger().WithField("pod", podName).WithError(err).Debug("LogScraper: log stream ended, will retry")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryBackoff):
+		}
+	}
+}
+
+func (s *LogScraper) streamOnce(ctx context.Context, podName string, podLabels map[string]string) error {
+	req := s.clientset.CoreV1().Pods(s.namespace).GetLogs(podName, &corev1.PodLogOptions{Follow: true})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, check := range s.checks {
+			if err := check.Check(podLabels, line); err == nil {
+				s.rpcChecker.recordLogMatch(check)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+// Package checker drives a tetragon.codegen/eventchecker.EventChecker
+// against the live event stream of a Tetragon instance running in a test
+// cluster, and reports whether the stream satisfied the checker.
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cilium/tetragon/api/v1/tetragon"
+	ec "github.com/cilium/tetragon/api/v1/tetragon/codegen/eventchecker"
+	"github.com/cilium/tetragon/pkg/logger"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+// RPCChecker drives an ec.EventChecker against the gRPC event stream
+// exported by Tetragon for the duration of a test.
+type RPCChecker struct {
+	checker ec.EventChecker
+	name    string
+
+	eventLimit int
+	timeLimit  time.Duration
+
+	mu           sync.Mutex
+	idleTimeout  time.Duration
+	resetSignal  chan struct{}
+	started      chan struct{}
+	startOnce    sync.Once
+	expectedLogs []*ec.PodLogChecker
+	matchedLogs  map[*ec.PodLogChecker]bool
+}
+
+// NewRPCChecker creates an RPCChecker that drives checker against the live
+// event stream. name is used in log output and failure messages.
+func NewRPCChecker(checker ec.EventChecker, name string) *RPCChecker {
+	return &RPCChecker{
+		checker: checker,
+		name:    name,
+		// Buffered so ResetTimeout never blocks: callers only need their
+		// reset to be noticed eventually, and concurrent resets can
+		// coalesce into a single pending one.
+		resetSignal: make(chan struct{}, 1),
+		started:     make(chan struct{}),
+		matchedLogs: make(map[*ec.PodLogChecker]bool),
+	}
+}
+
+// WithEventLimit stops the checker once it has observed limit events.
+func (r *RPCChecker) WithEventLimit(limit int) *RPCChecker {
+	r.eventLimit = limit
+	return r
+}
+
+// WithTimeLimit stops the checker after limit has elapsed since it started.
+func (r *RPCChecker) WithTimeLimit(limit time.Duration) *RPCChecker {
+	r.timeLimit = limit
+	return r
+}
+
+// withExpectedLogChecks registers checks a LogScraper is expected to
+// eventually satisfy. CheckInNamespace fails the test if any of them never
+// match. It's called by NewLogScraper, not test code directly.
+func (r *RPCChecker) withExpectedLogChecks(checks ...*ec.PodLogChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expectedLogs = append(r.expectedLogs, checks...)
+}
+
+// recordLogMatch marks check as satisfied and, like ResetTimeout, pushes
+// back the idle deadline since the workload just produced an expected
+// signal.
+func (r *RPCChecker) recordLogMatch(check *ec.PodLogChecker) {
+	r.mu.Lock()
+	r.matchedLogs[check] = true
+	r.mu.Unlock()
+	r.ResetTimeout()
+}
+
+// unmatchedLogChecks returns the expected log checks that never matched.
+func (r *RPCChecker) unmatchedLogChecks() []*ec.PodLogChecker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unmatched []*ec.PodLogChecker
+	for _, check := range r.expectedLogs {
+		if !r.matchedLogs[check] {
+			unmatched = append(unmatched, check)
+		}
+	}
+	return unmatched
+}
+
+// ResetTimeout pushes back the idle deadline, as if a fresh event had just
+// arrived. Callers that generate activity outside of the gRPC event stream
+// (e.g. a LogScraper or a flaky helm install) use this to tell the checker
+// "the test is still making progress, don't time out yet". It's called
+// concurrently from several goroutines, so it only ever signals run()'s own
+// loop rather than touching a shared timer directly.
+func (r *RPCChecker) ResetTimeout() {
+	select {
+	case r.resetSignal <- struct{}{}:
+	default:
+	}
+}
+
+// Wait returns a features.Func that blocks until the checker has started
+// consuming the event stream, or until timeout elapses.
+func (r *RPCChecker) Wait(timeout time.Duration) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		select {
+		case <-r.started:
+		case <-time.After(timeout):
+			t.Fatalf("%s: timed out waiting for checker to start", r.name)
+		}
+		return ctx
+	}
+}
+
+// CheckInNamespace returns a features.Func that subscribes to the Tetragon
+// event stream for namespace, drives r.checker against every event
+// observed, and fails the test if the checker (including any log checks
+// folded in via a LogScraper) does not complete successfully. timeout is an
+// idle timeout: it restarts on every event and every ResetTimeout call.
+func (r *RPCChecker) CheckInNamespace(timeout time.Duration, namespace string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		r.mu.Lock()
+		r.idleTimeout = timeout
+		r.mu.Unlock()
+
+		if err := r.run(ctx, c, namespace); err != nil {
+			t.Fatalf("%s: %s", r.name, err)
+		}
+		return ctx
+	}
+}
+
+// run connects to the Tetragon gRPC event server, feeds every event to
+// r.checker until it's satisfied or the event limit is hit, then performs a
+// FinalCheck and verifies every log check registered via a LogScraper
+// eventually matched. Hitting the time limit, the idle timeout or the end
+// of the stream returns an error without a FinalCheck.
+func (r *RPCChecker) run(ctx context.Context, c *envconf.Config, namespace string) error {
+	if r.timeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeLimit)
+		defer cancel()
+	}
+
+	conn, err := dialTetragon(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the tetragon gRPC server: %w", err)
+	}
+	defer conn.Close()
+
+	client := tetragon.NewFineGuidanceSensorsClient(conn)
+	stream, err := client.GetEvents(ctx, &tetragon.GetEventsRequest{
+		AllowList: []*tetragon.Filter{{Namespace: []string{namespace}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open tetragon event stream: %w", err)
+	}
+	r.startOnce.Do(func() { close(r.started) })
+
+	log := logger.GetLogger()
+	events := make(chan *tetragon.GetEventsResponse)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	r.mu.Lock()
+	idleTimeout := r.idleTimeout
+	r.mu.Unlock()
+
+	// timer is only ever touched from this goroutine: ResetTimeout (called
+	// from the event case below, a LogScraper, or a churn ticker) merely
+	// signals r.resetSignal, so there's never a concurrent Stop/Reset/drain
+	// on the same *time.Timer.
+	timer := time.NewTimer(idleTimeout)
+	defer timer.Stop()
+	resetTimer := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(idleTimeout)
+	}
+
+	var eventCount int
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", r.name, ctx.Err())
+		case <-timer.C:
+			return fmt.Errorf("%s: timed out waiting for events after %s idle", r.name, idleTimeout)
+		case err := <-recvErr:
+			return fmt.Errorf("%s: event stream ended: %w", r.name, err)
+		case <-r.resetSignal:
+			resetTimer()
+		case event := <-events:
+			resetTimer()
+			eventCount++
+
+			done, err := r.checker.NextEvent(event, log)
+			if err != nil {
+				return fmt.Errorf("%s: event check failed: %w", r.name, err)
+			}
+			if done {
+				return r.finalize(log)
+			}
+			if r.eventLimit > 0 && eventCount >= r.eventLimit {
+				return r.finalize(log)
+			}
+		}
+	}
+}
+
+// finalize runs the wrapped checker's FinalCheck and verifies every log
+// check registered via a LogScraper eventually matched.
+func (r *RPCChecker) finalize(log *logger.Logger) error {
+	if err := r.checker.FinalCheck(log); err != nil {
+		return fmt.Errorf("%s: %w", r.name, err)
+	}
+	if unmatched := r.unmatchedLogChecks(); len(unmatched) > 0 {
+		return fmt.Errorf("%s: %d expected log checks never matched: %v", r.name, len(unmatched), unmatched)
+	}
+	return nil
+}
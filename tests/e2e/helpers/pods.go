@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+package helpers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+)
+
+const (
+	// TetragonNamespace is the namespace the Tetragon DaemonSet runs in.
+	TetragonNamespace = "kube-system"
+	// TetragonAppSelector selects Tetragon's own pods.
+	TetragonAppSelector = "app.kubernetes.io/name=tetragon"
+	// TetragonGRPCPort is the port the Tetragon gRPC server listens on.
+	TetragonGRPCPort = 54321
+)
+
+// Clientset returns a kubernetes clientset built from the e2e-framework's
+// REST config, for callers that need API surface beyond what the
+// framework's own resource helpers expose (e.g. streaming pod logs).
+func Clientset(c *envconf.Config) (kubernetes.Interface, error) {
+	clientset, err := kubernetes.NewForConfig(c.Client().RESTConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes clientset: %w", err)
+	}
+	return clientset, nil
+}
+
+// ListPodsByLabelSelector lists the pods in namespace matching labelSelector
+// (a Kubernetes label selector expression, e.g. "app=frontend").
+func ListPodsByLabelSelector(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string) ([]corev1.Pod, error) {
+	list, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %q matching %q: %w", namespace, labelSelector, err)
+	}
+	return list.Items, nil
+}
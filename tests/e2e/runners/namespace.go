@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+package runners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+
+	"github.com/cilium/tetragon/tests/e2e/helpers"
+)
+
+// NamespaceFor generates a namespace name derived from baseName
+// ("<baseName>-<uuid>") and registers its creation and teardown with the
+// runner, so that parallel CI shards running the same e2e suite against one
+// shared cluster don't collide on namespace creation or deletion.
+//
+// Unlike a hard-coded namespace constant, the generated name is never
+// reused across runs, so setup only needs to create it (there is nothing
+// stale to delete first) and teardown only ever deletes the namespace this
+// call created. Call NamespaceFor once per suite, typically from TestMain,
+// in place of a shared namespace constant.
+func (r *Runner) NamespaceFor(baseName string) string {
+	namespace := fmt.Sprintf("%s-%s", baseName, uuid.NewString())
+
+	r.Setup(func(ctx context.Context, c *envconf.Config) (context.Context, error) {
+		ctx, err := helpers.CreateNamespace(namespace, true)(ctx, c)
+		if err != nil {
+			return ctx, fmt.Errorf("failed to create namespace %q: %w", namespace, err)
+		}
+		return ctx, nil
+	})
+
+	r.Finish(func(ctx context.Context, c *envconf.Config) (context.Context, error) {
+		return helpers.DeleteNamespace(namespace, true)(ctx, c)
+	})
+
+	return namespace
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+// Package runners provides the test harness shared by Tetragon's e2e
+// suites: a thin wrapper around sigs.k8s.io/e2e-framework's Environment
+// that adds Tetragon-specific setup (event export) and parallel feature
+// execution.
+package runners
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/e2e-framework/pkg/env"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+	"sigs.k8s.io/e2e-framework/pkg/types"
+
+	"github.com/cilium/tetragon/tests/e2e/helpers"
+)
+
+// Runner wraps an e2e-framework Environment with the setup every Tetragon
+// e2e suite needs (a Kubernetes config, Tetragon event export) so that
+// individual tests only have to describe their own features.
+type Runner struct {
+	cfg *envconf.Config
+	env env.Environment
+}
+
+// NewRunner creates an unconfigured Runner. Call Init before use.
+func NewRunner() *Runner {
+	return &Runner{}
+}
+
+// Init builds the Kubernetes config and underlying e2e-framework
+// Environment. It must be called once, typically from TestMain, before
+// any other Runner method.
+func (r *Runner) Init() *Runner {
+	cfg, err := envconf.NewFromFlags()
+	if err != nil {
+		panic("runners: failed to build envconf from flags: " + err.Error())
+	}
+	r.cfg = cfg
+	r.env = env.NewWithConfig(cfg)
+	return r
+}
+
+// Setup registers fn to run once before any test in this package.
+func (r *Runner) Setup(fn types.EnvFunc) *Runner {
+	r.env.Setup(fn)
+	return r
+}
+
+// Finish registers fn to run once after every test in this package has
+// completed, regardless of outcome.
+func (r *Runner) Finish(fn types.EnvFunc) *Runner {
+	r.env.Finish(fn)
+	return r
+}
+
+// SetupExport must be called at the start of every test function. It
+// registers a cleanup that, only if the test fails, dumps the Tetragon
+// DaemonSet's own logs (which include its event export) to t.Log so that a
+// failure attaches the exact export Tetragon produced during the test
+// instead of requiring a separate kubectl logs round-trip after the fact.
+func (r *Runner) SetupExport(t *testing.T) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+
+		ctx := context.Background()
+		clientset, err := helpers.Clientset(r.cfg)
+		if err != nil {
+			t.Logf("SetupExport: failed to build clientset to capture tetragon export: %s", err)
+			return
+		}
+
+		pods, err := helpers.ListPodsByLabelSelector(ctx, clientset, helpers.TetragonNamespace, helpers.TetragonAppSelector)
+		if err != nil {
+			t.Logf("SetupExport: failed to list tetragon pods to capture export: %s", err)
+			return
+		}
+
+		for _, pod := range pods {
+			logs, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+			if err != nil {
+				t.Logf("SetupExport: failed to fetch logs for %s/%s: %s", pod.Namespace, pod.Name, err)
+				continue
+			}
+			t.Logf("=== tetragon export from %s/%s ===\n%s", pod.Namespace, pod.Name, logs)
+		}
+	})
+}
+
+// Run executes the suite's tests via m and exits the process with the
+// resulting status code, as required by TestMain.
+func (r *Runner) Run(m *testing.M) {
+	os.Exit(r.env.Run(m))
+}
+
+// Test runs a single feature.
+func (r *Runner) Test(t *testing.T, f features.Feature) {
+	r.env.Test(t, f)
+}
+
+// TestInParallel runs every feature in fs concurrently against the same
+// environment and namespace, and returns once they have all completed.
+// This is used to run a workload and its event checker side by side: the
+// checker's Assess steps block on events while the workload's steps
+// generate them.
+func (r *Runner) TestInParallel(t *testing.T, fs ...features.Feature) {
+	var wg sync.WaitGroup
+	wg.Add(len(fs))
+	for _, f := range fs {
+		f := f
+		go func() {
+			defer wg.Done()
+			r.env.Test(t, f)
+		}()
+	}
+	wg.Wait()
+}
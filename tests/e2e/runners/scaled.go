@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of Tetragon
+
+package runners
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/cilium/tetragon/pkg/logger"
+	"github.com/cilium/tetragon/tests/e2e/helpers"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+// ScaleSpec configures the synthetic pod churn driven by
+// Runner.TestInParallelScaled.
+type ScaleSpec struct {
+	// Namespace and LabelSelector pick which deployments to churn.
+	Namespace     string
+	LabelSelector string
+
+	// Each deployment is periodically resized to a random replica count
+	// in [MinFactor*N, MaxFactor*N], where N is the deployment's original
+	// replica count.
+	MinFactor float64
+	MaxFactor float64
+
+	// Interval between scale updates.
+	Interval time.Duration
+
+	// Notify, if set, is called after every scale update. Tests use this
+	// to call RPCChecker.ResetTimeout so that deliberately churning pods
+	// doesn't trip the checker's idle-timeout watchdog.
+	Notify func()
+}
+
+// TestInParallelScaled runs fs the same way as TestInParallel, but also
+// continuously scales the deployments selected by spec up and down for the
+// duration of the test, to stress pod-label enrichment under churn rather
+// than against a steady-state workload.
+func (r *Runner) TestInParallelScaled(t *testing.T, spec ScaleSpec, fs ...features.Feature) {
+	clientset, err := helpers.Clientset(r.cfg)
+	if err != nil {
+		t.Fatalf("runners: failed to build clientset for scaled run: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go churnDeployments(ctx, clientset, spec)
+
+	r.TestInParallel(t, fs...)
+}
+
+// churnDeployments periodically resizes every deployment in
+// spec.Namespace matching spec.LabelSelector to a random replica count
+// within the configured scale factor range, until ctx is canceled.
+func churnDeployments(ctx context.Context, clientset kubernetes.Interface, spec ScaleSpec) {
+	ticker := time.NewTicker(spec.Interval)
+	defer ticker.Stop()
+
+	// original tracks each deployment's replica count before we started
+	// churning it, so repeated scale updates don't compound on top of a
+	// previous random factor.
+	original := make(map[string]int32)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deployments, err := clientset.AppsV1().Deployments(spec.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: spec.LabelSelector,
+			})
+			if err != nil {
+				logger.GetLogger().WithError(err).Debug("runners: failed to list deployments for scaled run")
+				continue
+			}
+
+			for _, d := range deployments.Items {
+				scaleDeployment(ctx, clientset, spec, d, original)
+			}
+
+			if spec.Notify != nil {
+				spec.Notify()
+			}
+		}
+	}
+}
+
+func scaleDeployment(ctx context.Context, clientset kubernetes.Interface, spec ScaleSpec, d appsv1.Deployment, original map[string]int32) {
+	base, ok := original[d.Name]
+	if !ok {
+		if d.Spec.Replicas != nil {
+			base = *d.Spec.Replicas
+		} else {
+			base = 1
+		}
+		original[d.Name] = base
+	}
+
+	factor := spec.MinFactor + rand.Float64()*(spec.MaxFactor-spec.MinFactor)
+	replicas := int32(math.Round(float64(base) * factor))
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	d.Spec.Replicas = &replicas
+	if _, err := clientset.AppsV1().Deployments(spec.Namespace).Update(ctx, &d, metav1.UpdateOptions{}); err != nil {
+		logger.GetLogger().WithError(err).WithField("deployment", d.Name).Debug(fmt.Sprintf("runners: failed to scale deployment to %d replicas", replicas))
+	}
+}
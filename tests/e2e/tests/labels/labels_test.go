@@ -24,11 +24,12 @@ import (
 // This holds our test environment which we get from calling runners.NewRunner().Setup()
 var runner *runners.Runner
 
-const (
-	// The namespace where we want to spawn our pods
-	namespace    = "labels"
-	demoAppRetry = 3
-)
+// namespace is the namespace where we spawn our pods. It's generated per
+// run by runners.NamespaceFor in TestMain so that parallel CI shards don't
+// collide on namespace creation/deletion in a shared cluster.
+var namespace string
+
+const demoAppRetry = 3
 
 func installDemoApp(labelsChecker *checker.RPCChecker) features.Func {
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
@@ -53,6 +54,27 @@ func installDemoApp(labelsChecker *checker.RPCChecker) features.Func {
 	}
 }
 
+// scrapeFrontendLogs attaches a LogScraper to the frontend pods so that,
+// alongside the exec events asserted by labelsEventChecker, we also confirm
+// the frontend container itself came up and started serving requests.
+func scrapeFrontendLogs(labelsChecker *checker.RPCChecker) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		clientset, err := helpers.Clientset(c)
+		if err != nil {
+			t.Fatalf("failed to build clientset for log scraper: %s", err)
+		}
+
+		scraper := checker.NewLogScraper(clientset, labelsChecker, namespace, "app=frontend",
+			ec.NewPodLogChecker("app=frontend", *sm.Regex("server listening on port")),
+		)
+		if err := scraper.Start(ctx); err != nil {
+			t.Fatalf("failed to start frontend log scraper: %s", err)
+		}
+		t.Cleanup(scraper.Wait)
+		return ctx
+	}
+}
+
 func uninstallDemoApp() features.Func {
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		manager := helm.New(c.KubeconfigFile())
@@ -69,17 +91,9 @@ func uninstallDemoApp() features.Func {
 func TestMain(m *testing.M) {
 	runner = runners.NewRunner().Init()
 
-	// Here we ensure our test namespace doesn't already exist then create it.
-	runner.Setup(func(ctx context.Context, c *envconf.Config) (context.Context, error) {
-		ctx, _ = helpers.DeleteNamespace(namespace, true)(ctx, c)
-
-		ctx, err := helpers.CreateNamespace(namespace, true)(ctx, c)
-		if err != nil {
-			return ctx, fmt.Errorf("failed to create namespace: %w", err)
-		}
-
-		return ctx, nil
-	})
+	// Generates a collision-free "labels-<uuid>" namespace and registers
+	// its setup/teardown with the runner.
+	namespace = runner.NamespaceFor("labels")
 
 	// Run the tests using the test runner.
 	runner.Run(m)
@@ -101,55 +115,64 @@ func TestLabelsDemoApp(t *testing.T) {
 		Assess("Wait for Checker", labelsChecker.Wait(30*time.Second)).
 		/* Run the workload */
 		Assess("Run Workload", installDemoApp(labelsChecker)).
+		/* Tail frontend pod logs as a second signal that the workload is alive */
+		Assess("Scrape Frontend Logs", scrapeFrontendLogs(labelsChecker)).
 		Feature()
 
 	uninstall := features.New("Uninstall Demo App").
 		Assess("Uninstall", uninstallDemoApp()).Feature()
 
+	// Churn every onlineboutique deployment's replica count between 0.5x
+	// and 1.5x its original size while the checks run, to stress
+	// Tetragon's pod-label enrichment under pod churn rather than just
+	// against a steady-state workload.
+	scaleSpec := runners.ScaleSpec{
+		Namespace: namespace,
+		MinFactor: 0.5,
+		MaxFactor: 1.5,
+		Interval:  30 * time.Second,
+		Notify:    labelsChecker.ResetTimeout,
+	}
+
 	// Spawn workload and run checker
-	runner.TestInParallel(t, runEventChecker, runWorkload)
+	runner.TestInParallelScaled(t, scaleSpec, runEventChecker, runWorkload)
 	runner.Test(t, uninstall)
 }
 
-func labelsEventChecker() *checker.RPCChecker {
-	labelsEventChecker := ec.NewUnorderedEventChecker(
-		ec.NewProcessExecChecker("adservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("adservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("cartservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("cartservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("checkoutservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("checkoutservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("currencyservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("currencyservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("emailservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("emailservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("frontend").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("frontend"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("loadgenerator").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("loadgenerator"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("paymentservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("paymentservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("productcatalogservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("productcatalogservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("recommendationservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("recommendationservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("redis").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("redis-cart"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
-		ec.NewProcessExecChecker("shippingservice").WithProcess(ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabels(map[string]sm.StringMatcher{
-			"app":               *sm.Full("shippingservice"),
-			"pod-template-hash": *sm.Regex("[a-f0-9]+")}))),
+// onlineboutiqueServices maps each onlineboutique process name to the
+// "app" label of the pod it should be observed running in. Most services
+// share a name, but redis is deployed under the "redis-cart" app label.
+var onlineboutiqueServices = map[string]string{
+	"adservice":             "adservice",
+	"cartservice":           "cartservice",
+	"checkoutservice":       "checkoutservice",
+	"currencyservice":       "currencyservice",
+	"emailservice":          "emailservice",
+	"frontend":              "frontend",
+	"loadgenerator":         "loadgenerator",
+	"paymentservice":        "paymentservice",
+	"productcatalogservice": "productcatalogservice",
+	"recommendationservice": "recommendationservice",
+	"redis":                 "redis-cart",
+	"shippingservice":       "shippingservice",
+}
+
+// execCheckerForService builds the exec checker shared by every
+// onlineboutique service: the process must run in a pod labeled with the
+// given app name and a pod-template-hash (i.e. a pod owned by a
+// ReplicaSet), expressed as a single label selector.
+func execCheckerForService(process, app string) *ec.ProcessExecChecker {
+	selector := fmt.Sprintf("app=%s,pod-template-hash", app)
+	return ec.NewProcessExecChecker(process).WithProcess(
+		ec.NewProcessChecker().WithPod(ec.NewPodChecker().WithPodLabelSelector(selector)),
 	)
+}
+
+func labelsEventChecker() *checker.RPCChecker {
+	checks := make([]ec.EventChecker, 0, len(onlineboutiqueServices))
+	for process, app := range onlineboutiqueServices {
+		checks = append(checks, execCheckerForService(process, app))
+	}
 
-	return checker.NewRPCChecker(labelsEventChecker, "labelsEventChecker")
+	return checker.NewRPCChecker(ec.NewUnorderedEventChecker(checks...), "labelsEventChecker")
 }